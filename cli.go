@@ -8,7 +8,8 @@ import (
 
 // CLIOptions holds the parsed command-line arguments.
 type CLIOptions struct {
-	Port string
+	Port         string
+	ControlToken string
 }
 
 // ParseCLI registers all CLI flags, parses them, and handles
@@ -22,14 +23,16 @@ func ParseCLI() CLIOptions {
 	var opts CLIOptions
 	flag.StringVar(&opts.Port, "p", "", "Port to run the server on (e.g. 8080)")
 	flag.StringVar(&opts.Port, "port", "", "Port to run the server on (e.g. 8080)")
+	flag.StringVar(&opts.ControlToken, "control-token", "", "Secret required to connect as a controller or use the HTTP /control endpoints; clients that omit or mismatch it connect read-only")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "OpenSplit %s — A real-time speedrun timer with WebSocket synchronization\n\n", Version)
 		fmt.Fprintf(os.Stderr, "Usage:\n  opensplit [flags]\n\n")
 		fmt.Fprintf(os.Stderr, "Flags:\n")
-		fmt.Fprintf(os.Stderr, "  -v, --version   Print the version and exit\n")
-		fmt.Fprintf(os.Stderr, "  -p, --port      Port to run the server on (default %q)\n", DefaultServerPort)
-		fmt.Fprintf(os.Stderr, "  -h, --help      Show this help message\n")
+		fmt.Fprintf(os.Stderr, "  -v, --version        Print the version and exit\n")
+		fmt.Fprintf(os.Stderr, "  -p, --port           Port to run the server on (default %q)\n", DefaultServerPort)
+		fmt.Fprintf(os.Stderr, "  --control-token      Secret that grants control access over WebSocket and HTTP; unset means anyone can control the timer\n")
+		fmt.Fprintf(os.Stderr, "  -h, --help           Show this help message\n")
 		fmt.Fprintf(os.Stderr, "\nThe port can also be set via the PORT environment variable.\n")
 		fmt.Fprintf(os.Stderr, "Precedence: --port flag > PORT env var > default (%s)\n", DefaultServerPort)
 	}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Role determines what a connected client is permitted to do.
+type Role string
+
+const (
+	// RoleController can send commands that mutate timer state.
+	RoleController Role = "controller"
+
+	// RoleSpectator only receives state broadcasts; any command it sends is
+	// rejected with an error frame.
+	RoleSpectator Role = "spectator"
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  WSReadBufferSize,
+	WriteBufferSize: WSWriteBufferSize,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// Client is a single WebSocket connection registered with the Hub.
+type Client struct {
+	hub  *Hub
+	conn *websocket.Conn
+	send chan []byte
+	role Role
+}
+
+// ServeWs upgrades an HTTP connection to a WebSocket and registers it with the hub.
+// The connection's role is decided by whether its ?token= query parameter matches
+// the hub's configured control token: a match, or no token configured at all, grants
+// RoleController; anything else is downgraded to RoleSpectator.
+func ServeWs(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Println("WebSocket upgrade failed:", err)
+		return
+	}
+
+	client := &Client{
+		hub:  hub,
+		conn: conn,
+		send: make(chan []byte, ClientSendBufferSize),
+		role: hub.roleFor(r.URL.Query().Get("token")),
+	}
+	client.hub.register <- client
+
+	go client.writePump()
+	go client.readPump()
+}
+
+// readPump reads commands off the WebSocket connection and forwards them to the hub.
+// Spectators' commands are dropped with an error frame instead of being applied.
+func (c *Client) readPump() {
+	defer func() {
+		c.hub.unregister <- c
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(MaxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(PongWait))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(PongWait))
+		return nil
+	})
+
+	for {
+		_, message, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				log.Println("WebSocket read error:", err)
+			}
+			break
+		}
+
+		if c.role != RoleController {
+			c.send <- errorFrame("spectators cannot send commands")
+			continue
+		}
+
+		c.hub.broadcast <- message
+	}
+}
+
+// writePump delivers messages queued for this client over the WebSocket connection
+// and pings the peer periodically to keep the connection alive.
+func (c *Client) writePump() {
+	ticker := time.NewTicker(PingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case message, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(WriteWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(WriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// errorFrame builds the JSON error frame sent back to a client whose command was
+// rejected.
+func errorFrame(message string) []byte {
+	data, _ := json.Marshal(map[string]string{"type": "error", "message": message})
+	return data
+}
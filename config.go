@@ -5,6 +5,10 @@ import (
 	"time"
 )
 
+// Version is the released version string, substituted at build time via
+// -ldflags "-X main.Version=...". Defaults to "dev" for local builds.
+var Version = "dev"
+
 const (
 	// DefaultServerPort is the default HTTP server port.
 	DefaultServerPort = ":8080"
@@ -15,6 +19,15 @@ const (
 	// DefaultSplitIcon is the default emoji icon for splits.
 	DefaultSplitIcon = "🏃"
 
+	// DefaultHistoryPath is where the run history (completed and reset attempts) is
+	// persisted between server restarts.
+	DefaultHistoryPath = "opensplit_history.json"
+
+	// DefaultCleanSumOfBestThreshold is the fraction of a split's median segment
+	// time below which a recorded segment is treated as an outlier (e.g. an
+	// accidental double-tap) and discarded by TimerState.CleanSumOfBest.
+	DefaultCleanSumOfBestThreshold = 0.5
+
 	// TimerTickInterval is how often the timer state is broadcast to clients while running.
 	TimerTickInterval = 100 * time.Millisecond
 
@@ -40,13 +53,19 @@ const (
 	ClientSendBufferSize = 256
 )
 
-// GetServerPort returns the server port, checking the PORT environment variable first.
-func GetServerPort() string {
-	if port := os.Getenv("PORT"); port != "" {
-		if port[0] != ':' {
-			return ":" + port
-		}
-		return port
+// GetServerPort returns the server port to listen on, following the precedence
+// documented in ParseCLI's usage text: the --port flag, then the PORT environment
+// variable, then DefaultServerPort.
+func GetServerPort(flagPort string) string {
+	port := flagPort
+	if port == "" {
+		port = os.Getenv("PORT")
+	}
+	if port == "" {
+		return DefaultServerPort
+	}
+	if port[0] != ':' {
+		return ":" + port
 	}
-	return DefaultServerPort
+	return port
 }
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// AttemptRecord captures the full split history of a single attempt, whether it
+// finished or was reset early. It is the unit of storage for HistoryStore.
+type AttemptRecord struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Completed bool          `json:"completed"`
+	FinalTime time.Duration `json:"finalTime"`
+	Splits    []Split       `json:"splits"`
+}
+
+// RunSummary is the condensed view of an AttemptRecord returned by listRuns, without
+// the full per-split payload.
+type RunSummary struct {
+	Timestamp  time.Time     `json:"timestamp"`
+	Completed  bool          `json:"completed"`
+	FinalTime  time.Duration `json:"finalTime"`
+	SplitCount int           `json:"splitCount"`
+}
+
+// HistoryStore persists every completed or reset attempt to disk so that run history
+// survives restarts and can be exported to other speedrun timers.
+type HistoryStore struct {
+	path     string
+	Attempts []AttemptRecord `json:"attempts"`
+}
+
+// NewHistoryStore creates a HistoryStore backed by the JSON file at path, loading any
+// history already recorded there.
+func NewHistoryStore(path string) *HistoryStore {
+	hs := &HistoryStore{path: path}
+	hs.load()
+	return hs
+}
+
+// load reads existing attempt history from disk. A missing or unreadable file just
+// means there is no history yet, which is not an error.
+func (hs *HistoryStore) load() {
+	data, err := os.ReadFile(hs.path)
+	if err != nil {
+		return
+	}
+	_ = json.Unmarshal(data, hs)
+}
+
+// save writes the current attempt history to disk as JSON.
+func (hs *HistoryStore) save() error {
+	data, err := json.MarshalIndent(hs, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(hs.path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(hs.path, data, 0o644)
+}
+
+// RecordAttempt appends the given timer state's splits as a new attempt and persists
+// the store. completed should be true when the run finished (all splits reached) and
+// false when it was abandoned via reset.
+func (hs *HistoryStore) RecordAttempt(ts *TimerState, completed bool) error {
+	splits := make([]Split, len(ts.Splits))
+	copy(splits, ts.Splits)
+
+	hs.Attempts = append(hs.Attempts, AttemptRecord{
+		Timestamp: time.Now(),
+		Completed: completed,
+		FinalTime: ts.CurrentTime,
+		Splits:    splits,
+	})
+	return hs.save()
+}
+
+// Summaries returns the condensed view of every recorded attempt, most recent last.
+func (hs *HistoryStore) Summaries() []RunSummary {
+	summaries := make([]RunSummary, len(hs.Attempts))
+	for i, a := range hs.Attempts {
+		summaries[i] = RunSummary{
+			Timestamp:  a.Timestamp,
+			Completed:  a.Completed,
+			FinalTime:  a.FinalTime,
+			SplitCount: len(a.Splits),
+		}
+	}
+	return summaries
+}
@@ -22,17 +22,47 @@ type Hub struct {
 
 	// Timer state
 	timer *TimerState
+
+	// Persisted run history
+	history *HistoryStore
+
+	// controlToken gates RoleController access over WebSocket and the HTTP control
+	// endpoints. An empty token means anyone connects as a controller.
+	controlToken string
 }
 
-// NewHub creates a new hub
-func NewHub() *Hub {
+// NewHub creates a new hub. controlToken gates control access; pass "" to allow
+// everyone to connect as a controller.
+func NewHub(controlToken string) *Hub {
 	return &Hub{
-		broadcast:  make(chan []byte),
-		register:   make(chan *Client),
-		unregister: make(chan *Client),
-		clients:    make(map[*Client]bool),
-		timer:      NewTimerState(),
+		broadcast:    make(chan []byte),
+		register:     make(chan *Client),
+		unregister:   make(chan *Client),
+		clients:      make(map[*Client]bool),
+		timer:        NewTimerState(),
+		history:      NewHistoryStore(DefaultHistoryPath),
+		controlToken: controlToken,
+	}
+}
+
+// roleFor determines the Role a connecting WebSocket client should get based on the
+// token it presented.
+func (h *Hub) roleFor(token string) Role {
+	if h.controlToken == "" || token == h.controlToken {
+		return RoleController
 	}
+	return RoleSpectator
+}
+
+// Submit enqueues a command from a non-WebSocket source (e.g. the HTTP control
+// endpoints) as if it had been sent by a controller client.
+func (h *Hub) Submit(cmd map[string]interface{}) {
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		log.Println("Failed to marshal submitted command:", err)
+		return
+	}
+	h.broadcast <- data
 }
 
 // broadcastState sends the current timer state to all connected clients.
@@ -57,16 +87,174 @@ func (h *Hub) handleCommand(cmd map[string]interface{}) {
 	case "pause":
 		h.timer.Pause()
 	case "reset":
-		h.timer.Reset()
+		h.handleReset()
 	case "setSplits":
 		h.handleSetSplits(cmd)
 	case "nextSplit":
-		h.timer.NextSplit()
+		h.handleNextSplit()
+	case "undoSplit":
+		h.timer.UndoSplit()
+	case "skipSplit":
+		h.handleSkipSplit()
+	case "pauseGameTime":
+		h.timer.PauseGameTime()
+	case "resumeGameTime":
+		h.timer.ResumeGameTime()
 	case "restorePBData":
 		h.timer.RestorePBData(cmd)
 	case "setWorldRecord":
-		if worldRecord, ok := cmd["worldRecord"].(float64); ok {
-			h.timer.WorldRecord = time.Duration(worldRecord)
+		h.handleSetWorldRecord(cmd)
+	case "setComparison":
+		if comparison, ok := cmd["comparison"].(string); ok {
+			h.timer.Comparison = comparison
+		}
+	case "exportSplits":
+		h.handleExportSplits(cmd)
+	case "importSplits":
+		h.handleImportSplits(cmd)
+	case "listRuns":
+		h.broadcastEvent("runList", map[string]interface{}{"runs": h.history.Summaries()})
+	case "cleanSumOfBest":
+		threshold := DefaultCleanSumOfBestThreshold
+		if t, ok := cmd["threshold"].(float64); ok {
+			threshold = t
+		}
+		h.timer.CleanSumOfBest(threshold)
+	}
+}
+
+// handleReset records the in-progress attempt (if any) as an incomplete run before
+// clearing the timer state. A run that already finished (Status == "stopped" with
+// splits recorded) was already logged as a completed attempt by handleNextSplit or
+// handleSkipSplit, so it isn't recorded again here.
+func (h *Hub) handleReset() {
+	if len(h.timer.Splits) > 0 && h.timer.Status != "stopped" {
+		if err := h.history.RecordAttempt(h.timer, false); err != nil {
+			log.Println("Failed to record attempt history:", err)
+		}
+	}
+	h.timer.Reset()
+}
+
+// handleNextSplit advances the timer and, once a run finishes, records it as a
+// completed attempt in the history store.
+func (h *Hub) handleNextSplit() {
+	wasLastSplit := h.timer.CurrentSplitIndex == len(h.timer.PredefinedSplits)-1
+	h.timer.NextSplit()
+	h.recordIfFinished(wasLastSplit)
+}
+
+// handleSkipSplit advances past the current split and, like handleNextSplit, records
+// a completed attempt if skipping the final split finished the run.
+func (h *Hub) handleSkipSplit() {
+	wasLastSplit := h.timer.CurrentSplitIndex == len(h.timer.PredefinedSplits)-1
+	h.timer.SkipSplit()
+	h.recordIfFinished(wasLastSplit)
+}
+
+// recordIfFinished records the current run as a completed attempt if advancing past
+// the last split just finished it.
+func (h *Hub) recordIfFinished(wasLastSplit bool) {
+	if wasLastSplit && h.timer.Status == "stopped" && len(h.timer.Splits) > 0 {
+		if err := h.history.RecordAttempt(h.timer, true); err != nil {
+			log.Println("Failed to record attempt history:", err)
+		}
+	}
+}
+
+// handleSetWorldRecord updates the stored world record time and, if provided, its
+// per-split cumulative times so the "worldRecord" comparison has something to show.
+func (h *Hub) handleSetWorldRecord(cmd map[string]interface{}) {
+	if worldRecord, ok := cmd["worldRecord"].(float64); ok {
+		h.timer.WorldRecord = time.Duration(worldRecord)
+	}
+
+	splits, ok := cmd["worldRecordSplits"].([]interface{})
+	if !ok {
+		return
+	}
+	wrSplits := make([]time.Duration, len(splits))
+	for i, val := range splits {
+		if floatVal, ok := val.(float64); ok {
+			wrSplits[i] = time.Duration(floatVal)
+		}
+	}
+	h.timer.WorldRecordSplits = wrSplits
+}
+
+// handleExportSplits renders the current splits and history as JSON or LiveSplit
+// .lss and broadcasts the result as a one-off export event.
+func (h *Hub) handleExportSplits(cmd map[string]interface{}) {
+	format, _ := cmd["format"].(string)
+
+	var (
+		data []byte
+		err  error
+	)
+	switch format {
+	case "lss":
+		data, err = ExportLSS(h.timer, h.history)
+	default:
+		format = "json"
+		data, err = json.Marshal(h.history)
+	}
+	if err != nil {
+		log.Println("Failed to export splits:", err)
+		return
+	}
+
+	h.broadcastEvent("export", map[string]interface{}{
+		"format": format,
+		"data":   string(data),
+	})
+}
+
+// handleImportSplits parses an imported splits payload and applies it to the timer
+// state. The "lss" format expects raw LiveSplit XML in cmd["data"]; any other format
+// is treated as the app's own restorePBData-style JSON blob.
+func (h *Hub) handleImportSplits(cmd map[string]interface{}) {
+	format, _ := cmd["format"].(string)
+	data, _ := cmd["data"].(string)
+	if data == "" {
+		return
+	}
+
+	switch format {
+	case "lss":
+		imported, err := ImportLSS([]byte(data))
+		if err != nil {
+			log.Println("Failed to import .lss splits:", err)
+			return
+		}
+		h.timer.ApplyImportedSplits(imported)
+	default:
+		var payload map[string]interface{}
+		if err := json.Unmarshal([]byte(data), &payload); err != nil {
+			log.Println("Failed to import splits:", err)
+			return
+		}
+		h.timer.RestorePBData(payload)
+	}
+}
+
+// broadcastEvent sends a one-off, non-state message to every connected client. Unlike
+// broadcastState, the payload is an arbitrary event envelope rather than the timer.
+func (h *Hub) broadcastEvent(eventType string, payload map[string]interface{}) {
+	envelope := map[string]interface{}{"type": eventType}
+	for k, v := range payload {
+		envelope[k] = v
+	}
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		log.Println("Failed to marshal event:", err)
+		return
+	}
+	for client := range h.clients {
+		select {
+		case client.send <- data:
+		default:
+			close(client.send)
+			delete(h.clients, client)
 		}
 	}
 }
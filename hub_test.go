@@ -0,0 +1,67 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestHub(t *testing.T) *Hub {
+	h := NewHub("")
+	h.history = NewHistoryStore(filepath.Join(t.TempDir(), "history.json"))
+	defs := []SplitDefinition{{Name: "Split 1"}, {Name: "Split 2"}}
+	h.timer.SetPredefinedSplits(defs, "Test")
+	return h
+}
+
+func TestHandleReset_DoesNotDoubleRecordAFinishedRun(t *testing.T) {
+	h := newTestHub(t)
+	h.timer.Start()
+	time.Sleep(2 * time.Millisecond)
+	h.handleNextSplit() // split 1
+	time.Sleep(2 * time.Millisecond)
+	h.handleNextSplit() // split 2, the last one -> run finishes and is recorded
+
+	h.handleReset()
+
+	attempts := h.history.Summaries()
+	if len(attempts) != 1 {
+		t.Fatalf("len(Summaries()) = %d, want 1 (finished run recorded once)", len(attempts))
+	}
+	if !attempts[0].Completed {
+		t.Errorf("Summaries()[0].Completed = false, want true")
+	}
+}
+
+func TestHandleReset_RecordsAnAbandonedRun(t *testing.T) {
+	h := newTestHub(t)
+	h.timer.Start()
+	time.Sleep(2 * time.Millisecond)
+	h.handleNextSplit() // split 1 only; run not finished
+
+	h.handleReset()
+
+	attempts := h.history.Summaries()
+	if len(attempts) != 1 {
+		t.Fatalf("len(Summaries()) = %d, want 1 (abandoned run recorded)", len(attempts))
+	}
+	if attempts[0].Completed {
+		t.Errorf("Summaries()[0].Completed = true, want false")
+	}
+}
+
+func TestHandleSkipSplit_RecordsARunFinishedBySkippingTheFinalSplit(t *testing.T) {
+	h := newTestHub(t)
+	h.timer.Start()
+	time.Sleep(2 * time.Millisecond)
+	h.handleNextSplit() // split 1
+	h.handleSkipSplit() // split 2, the last one -> skipping it finishes the run
+
+	attempts := h.history.Summaries()
+	if len(attempts) != 1 {
+		t.Fatalf("len(Summaries()) = %d, want 1 (run finished via skip recorded)", len(attempts))
+	}
+	if !attempts[0].Completed {
+		t.Errorf("Summaries()[0].Completed = false, want true")
+	}
+}
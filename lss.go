@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The types below mirror the subset of the LiveSplit .lss XML schema that OpenSplit
+// uses: segment names/icons, best segment time, segment history and the
+// personal-best split times, plus the attempt log. ExportLSS writes all of these;
+// ImportLSS reads back only the segment names/icons, best segment times and
+// personal-best split times, so segment history and the attempt log are not
+// imported. Fields LiveSplit writes that these types do not declare (autosplitter
+// settings, variables, ...) are dropped on import and not written on export.
+
+type lssRun struct {
+	XMLName        xml.Name       `xml:"Run"`
+	Version        string         `xml:"version,attr"`
+	GameName       string         `xml:"GameName"`
+	CategoryName   string         `xml:"CategoryName"`
+	AttemptCount   int            `xml:"AttemptCount"`
+	AttemptHistory lssAttemptList `xml:"AttemptHistory"`
+	Segments       lssSegmentList `xml:"Segments"`
+}
+
+type lssAttemptList struct {
+	Attempts []lssAttempt `xml:"Attempt"`
+}
+
+type lssAttempt struct {
+	ID       int    `xml:"id,attr"`
+	RealTime string `xml:"realTime,attr,omitempty"`
+}
+
+type lssSegmentList struct {
+	Segments []lssSegment `xml:"Segment"`
+}
+
+type lssSegment struct {
+	Name            string         `xml:"Name"`
+	Icon            string         `xml:"Icon"`
+	SplitTimes      lssSplitTimes  `xml:"SplitTimes"`
+	BestSegmentTime lssTime        `xml:"BestSegmentTime"`
+	SegmentHistory  lssSegmentHist `xml:"SegmentHistory"`
+}
+
+type lssSplitTimes struct {
+	SplitTime []lssNamedTime `xml:"SplitTime"`
+}
+
+type lssNamedTime struct {
+	Name     string      `xml:"name,attr"`
+	RealTime lssRealTime `xml:"RealTime"`
+}
+
+type lssTime struct {
+	RealTime lssRealTime `xml:"RealTime"`
+}
+
+type lssSegmentHist struct {
+	Time []lssHistEntry `xml:"Time"`
+}
+
+type lssHistEntry struct {
+	ID       int         `xml:"id,attr"`
+	RealTime lssRealTime `xml:"RealTime"`
+}
+
+type lssRealTime struct {
+	Value string `xml:",chardata"`
+}
+
+const lssPersonalBestName = "Personal Best"
+
+// formatLSSTime renders a duration in LiveSplit's "H:MM:SS.fffffff" real-time format.
+// A zero or negative duration (our "no time recorded" sentinel) is rendered as the
+// empty string, so such a time is written as an empty RealTime node.
+func formatLSSTime(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+	d -= seconds * time.Second
+	fraction := d.Seconds()
+
+	return fmt.Sprintf("%d:%02d:%010.7f", hours, minutes, float64(seconds)+fraction)
+}
+
+// parseLSSTime parses LiveSplit's "H:MM:SS.fffffff" real-time format back into a
+// time.Duration. An empty string parses to 0.
+func parseLSSTime(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("lss: invalid time %q", s)
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("lss: invalid hours in %q: %w", s, err)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("lss: invalid minutes in %q: %w", s, err)
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, fmt.Errorf("lss: invalid seconds in %q: %w", s, err)
+	}
+
+	total := time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds*float64(time.Second))
+	return total, nil
+}
+
+// ExportLSS renders the timer state and its recorded history as a LiveSplit .lss file.
+// Only completed attempts are exported: LiveSplit's AttemptHistory and per-segment
+// SegmentHistory ids are the same sequence, so AttemptCount and every <Time id=N> must
+// agree on which attempts exist, and an abandoned (reset) run has no place in either.
+func ExportLSS(ts *TimerState, history *HistoryStore) ([]byte, error) {
+	var completed []AttemptRecord
+	for _, attempt := range history.Attempts {
+		if attempt.Completed {
+			completed = append(completed, attempt)
+		}
+	}
+
+	run := lssRun{
+		Version:      "1.7.0",
+		GameName:     ts.TimerTitle,
+		CategoryName: "",
+		AttemptCount: len(completed),
+	}
+
+	for i, attempt := range completed {
+		run.AttemptHistory.Attempts = append(run.AttemptHistory.Attempts, lssAttempt{
+			ID:       i + 1,
+			RealTime: formatLSSTime(attempt.FinalTime),
+		})
+	}
+
+	for i, def := range ts.PredefinedSplits {
+		seg := lssSegment{
+			Name: def.Name,
+			Icon: def.Icon,
+		}
+
+		if i < len(ts.BestSplitTimes) {
+			seg.BestSegmentTime.RealTime.Value = formatLSSTime(ts.BestSplitTimes[i])
+		}
+		if i < len(ts.PBSplitTimes) {
+			seg.SplitTimes.SplitTime = []lssNamedTime{{
+				Name:     lssPersonalBestName,
+				RealTime: lssRealTime{Value: formatLSSTime(ts.PBSplitTimes[i])},
+			}}
+		}
+
+		for attemptID, attempt := range completed {
+			if i >= len(attempt.Splits) {
+				continue
+			}
+			seg.SegmentHistory.Time = append(seg.SegmentHistory.Time, lssHistEntry{
+				ID:       attemptID + 1,
+				RealTime: lssRealTime{Value: formatLSSTime(attempt.Splits[i].SegmentTime)},
+			})
+		}
+
+		run.Segments.Segments = append(run.Segments.Segments, seg)
+	}
+
+	out, err := xml.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// ImportedSplits holds everything ImportLSS recovers from a .lss file, ready to be
+// applied to a TimerState.
+type ImportedSplits struct {
+	Title            string
+	PredefinedSplits []SplitDefinition
+	BestSplitTimes   []time.Duration
+	PBSplitTimes     []time.Duration
+}
+
+// ImportLSS parses a LiveSplit .lss file and extracts the segment definitions, best
+// segment times and personal-best split times it contains.
+func ImportLSS(data []byte) (*ImportedSplits, error) {
+	var run lssRun
+	if err := xml.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("lss: parse: %w", err)
+	}
+
+	imported := &ImportedSplits{Title: run.GameName}
+
+	for _, seg := range run.Segments.Segments {
+		icon := seg.Icon
+		if icon == "" {
+			icon = DefaultSplitIcon
+		}
+		imported.PredefinedSplits = append(imported.PredefinedSplits, SplitDefinition{
+			Name: seg.Name,
+			Icon: icon,
+		})
+
+		best, err := parseLSSTime(seg.BestSegmentTime.RealTime.Value)
+		if err != nil {
+			return nil, err
+		}
+		imported.BestSplitTimes = append(imported.BestSplitTimes, best)
+
+		var pb time.Duration
+		for _, st := range seg.SplitTimes.SplitTime {
+			if st.Name == lssPersonalBestName {
+				pb, err = parseLSSTime(st.RealTime.Value)
+				if err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+		imported.PBSplitTimes = append(imported.PBSplitTimes, pb)
+	}
+
+	return imported, nil
+}
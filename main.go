@@ -2,9 +2,12 @@ package main
 
 import (
 	"embed"
+	"encoding/json"
+	"fmt"
 	"io/fs"
 	"log"
 	"net/http"
+	"strings"
 )
 
 //go:embed static/*
@@ -13,13 +16,21 @@ var staticFS embed.FS
 func main() {
 	opts := ParseCLI()
 
-	hub := NewHub()
+	hub := NewHub(opts.ControlToken)
 	go hub.Run()
 
 	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
 		ServeWs(hub, w, r)
 	})
 
+	http.HandleFunc("/export", func(w http.ResponseWriter, r *http.Request) {
+		handleExport(hub, w, r)
+	})
+
+	http.HandleFunc("/control/", func(w http.ResponseWriter, r *http.Request) {
+		handleControl(hub, opts.ControlToken, w, r)
+	})
+
 	staticSub, _ := fs.Sub(staticFS, "static")
 	http.Handle("/", http.FileServer(http.FS(staticSub)))
 
@@ -27,3 +38,70 @@ func main() {
 	log.Printf("Server starting on %s", port)
 	log.Fatal(http.ListenAndServe(port, nil))
 }
+
+// handleExport serves the current splits and run history as a downloadable file in
+// either the app's own JSON format or LiveSplit's .lss XML format, selected via the
+// ?format= query parameter (defaults to json).
+func handleExport(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+
+	var (
+		data        []byte
+		err         error
+		contentType string
+		filename    string
+	)
+	switch format {
+	case "lss":
+		data, err = ExportLSS(hub.timer, hub.history)
+		contentType = "application/xml"
+		filename = "opensplit.lss"
+	default:
+		format = "json"
+		data, err = json.Marshal(hub.history)
+		contentType = "application/json"
+		filename = "opensplit_history.json"
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Write(data)
+}
+
+// controlCommands maps the /control/{action} path segment to the WS command name it
+// submits to the hub.
+var controlCommands = map[string]string{
+	"start": "start",
+	"pause": "pause",
+	"split": "nextSplit",
+	"reset": "reset",
+}
+
+// handleControl lets external tools (AutoHotkey, a Stream Deck plugin, ...) drive
+// the timer over a plain HTTP POST instead of a WebSocket connection, guarded by the
+// same control token used to negotiate the WebSocket controller/spectator role.
+func handleControl(hub *Hub, controlToken string, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if controlToken != "" && r.URL.Query().Get("token") != controlToken {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	action := strings.TrimPrefix(r.URL.Path, "/control/")
+	command, ok := controlCommands[action]
+	if !ok {
+		http.Error(w, "unknown action", http.StatusNotFound)
+		return
+	}
+
+	hub.Submit(map[string]interface{}{"command": command})
+	w.WriteHeader(http.StatusNoContent)
+}
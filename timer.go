@@ -2,13 +2,51 @@ package main
 
 import (
 	"encoding/json"
+	"math"
+	"sort"
 	"time"
 )
 
+// SkippedSegmentTime marks a segment history entry the runner advanced past without
+// a real time (see TimerState.SkipSplit). It is distinct from the zero value, which
+// means "no attempt has reached this split yet".
+const SkippedSegmentTime time.Duration = -1
+
+// Comparison selects which reference splits Delta and ComparisonTimes are measured
+// against.
+const (
+	ComparisonPB           = "pb"
+	ComparisonBestSegments = "bestSegments"
+	ComparisonAverage      = "average"
+	ComparisonWorldRecord  = "worldRecord"
+	ComparisonSumOfBest    = "sumOfBest"
+)
+
+// Delta color states, mirroring LiveSplit's split coloring: ahead/behind the
+// comparison overall, and gaining/losing ground on this particular segment, plus the
+// special "gold" state for a new best segment.
+const (
+	DeltaAheadGaining  = "ahead gaining"
+	DeltaAheadLosing   = "ahead losing"
+	DeltaBehindGaining = "behind gaining"
+	DeltaBehindLosing  = "behind losing"
+	DeltaGold          = "gold"
+)
+
 // SplitDefinition represents a predefined split with name and icon
 type SplitDefinition struct {
-	Name string `json:"name"`
-	Icon string `json:"icon"` // base64 encoded image or emoji
+	Name  string `json:"name"`
+	Icon  string `json:"icon"`  // base64 encoded image or emoji
+	Notes string `json:"notes"` // optional free-form notes for the split
+}
+
+// SegmentStat summarizes a split's recorded segment history for the frontend's
+// statistics panel.
+type SegmentStat struct {
+	Average          time.Duration `json:"average"`
+	Median           time.Duration `json:"median"`
+	StdDev           time.Duration `json:"stdDev"`
+	PossibleTimeSave time.Duration `json:"possibleTimeSave"` // Average minus best segment time
 }
 
 // Split represents a single split with name, segment time, cumulative time, and delta
@@ -16,7 +54,17 @@ type Split struct {
 	Name           string        `json:"name"`
 	SegmentTime    time.Duration `json:"segmentTime"`
 	CumulativeTime time.Duration `json:"cumulativeTime"`
-	Delta          time.Duration `json:"delta"` // Difference from best cumulative time (negative = ahead, positive = behind)
+	Delta          time.Duration `json:"delta"`      // Difference from the comparison's cumulative time (negative = ahead, positive = behind)
+	DeltaState     string        `json:"deltaState"` // One of the Delta* color states
+	TimeSaved      time.Duration `json:"timeSaved"`  // Best segment time prior to this attempt minus the time actually run (positive = saved)
+
+	// The fields below are not serialized. They stash whatever best-time state was
+	// in effect immediately before this split was recorded, so UndoSplit can put it
+	// back exactly rather than guessing at a recomputation.
+	previousBestSplitTime      time.Duration
+	previousBestCumulativeTime time.Duration
+	previousPersonalBest       time.Duration
+	previousPBSplitTimes       []time.Duration
 }
 
 // TimerState holds the current state of the timer
@@ -37,6 +85,35 @@ type TimerState struct {
 	SumOfBest           time.Duration   `json:"sumOfBest"`           // Sum of all best segment times
 	PBSplitTimes        []time.Duration `json:"pbSplitTimes"`        // Cumulative times from the personal best run
 	WorldRecord         time.Duration   `json:"worldRecord"`         // World record time
+	WorldRecordSplits   []time.Duration `json:"worldRecordSplits"`   // Cumulative times from the stored world record run
+
+	// SegmentHistory records every segment time ever completed for each split (keyed
+	// by split index), across all attempts including ones later reset. A value of
+	// SkippedSegmentTime marks a skipped segment. It is the basis for sum-of-best
+	// optimization and the statistics panel, but is not serialized directly — see
+	// SegmentStats.
+	SegmentHistory [][]time.Duration `json:"-"`
+
+	// SegmentStats holds the average/median/standard-deviation of each split's
+	// segment history, recomputed whenever the history changes.
+	SegmentStats []SegmentStat `json:"segmentStats"`
+
+	// Comparison selects which reference splits Delta/ComparisonTimes are measured
+	// against. Defaults to ComparisonPB.
+	Comparison string `json:"comparison"`
+
+	// ComparisonTimes is the cumulative time for each split under the active
+	// Comparison, recomputed on the fly whenever the state is serialized.
+	ComparisonTimes []time.Duration `json:"comparisonTimes"`
+
+	// GameTime is a second, independently-pausable clock (e.g. for Real-Time-Attack
+	// vs In-Game-Time) that ticks alongside CurrentTime while running, except while
+	// GameTimePaused (such as during a loading screen). GameTimeOffset is the
+	// cumulative duration GameTime has been paused for; it is what lets GameTime
+	// equal CurrentTime-GameTimeOffset without drifting across pauses.
+	GameTime       time.Duration `json:"gameTime"`
+	GameTimePaused bool          `json:"gameTimePaused"`
+	GameTimeOffset time.Duration `json:"-"`
 }
 
 // NewTimerState creates a new timer state
@@ -54,6 +131,14 @@ func NewTimerState() *TimerState {
 		SumOfBest:           0,
 		PBSplitTimes:        []time.Duration{},
 		WorldRecord:         0,
+		WorldRecordSplits:   []time.Duration{},
+		SegmentHistory:      [][]time.Duration{},
+		SegmentStats:        []SegmentStat{},
+		Comparison:          ComparisonPB,
+		ComparisonTimes:     []time.Duration{},
+		GameTime:            0,
+		GameTimePaused:      false,
+		GameTimeOffset:      0,
 	}
 }
 
@@ -64,6 +149,9 @@ func (ts *TimerState) Start() {
 	case "stopped":
 		ts.StartTime = now
 		ts.PausedAt = 0
+		ts.GameTime = 0
+		ts.GameTimePaused = false
+		ts.GameTimeOffset = 0
 		// Set current split index to 0 (first split) when starting from stopped
 		if len(ts.PredefinedSplits) > 0 {
 			ts.CurrentSplitIndex = 0
@@ -94,6 +182,9 @@ func (ts *TimerState) Reset() {
 	ts.CurrentSplitIndex = -1
 	ts.StartTime = time.Time{}
 	ts.PausedAt = 0
+	ts.GameTime = 0
+	ts.GameTimePaused = false
+	ts.GameTimeOffset = 0
 }
 
 // SetPredefinedSplits sets the predefined splits
@@ -111,6 +202,13 @@ func (ts *TimerState) SetPredefinedSplits(splits []SplitDefinition, title string
 		ts.BestCumulativeTimes = make([]time.Duration, numSplits)
 		ts.PBSplitTimes = make([]time.Duration, numSplits)
 	}
+	if len(ts.WorldRecordSplits) != numSplits {
+		ts.WorldRecordSplits = make([]time.Duration, numSplits)
+	}
+	if len(ts.SegmentHistory) != numSplits {
+		ts.SegmentHistory = make([][]time.Duration, numSplits)
+		ts.updateSegmentStats()
+	}
 }
 
 // NextSplit advances to the next predefined split
@@ -119,41 +217,63 @@ func (ts *TimerState) NextSplit() {
 		return
 	}
 
-	// Calculate times for the current split being completed
-	segmentTime := time.Since(ts.StartTime)
-	if len(ts.Splits) > 0 {
-		segmentTime -= ts.Splits[len(ts.Splits)-1].CumulativeTime
+	idx := ts.CurrentSplitIndex
+
+	// Stash the best-time state in effect before this split, so UndoSplit can put
+	// it back exactly if this split turns out to set a new best.
+	var previousBest, previousBestCumulative time.Duration
+	if idx < len(ts.BestSplitTimes) {
+		previousBest = ts.BestSplitTimes[idx]
+	}
+	if idx < len(ts.BestCumulativeTimes) {
+		previousBestCumulative = ts.BestCumulativeTimes[idx]
 	}
+	previousPersonalBest := ts.PersonalBest
+	previousPBSplitTimes := append([]time.Duration(nil), ts.PBSplitTimes...)
+
+	// Calculate times for the current split being completed
+	segmentTime := time.Since(ts.StartTime) - ts.lastRealCumulativeTime()
 	ts.CurrentTime = time.Since(ts.StartTime)
 
-	// Calculate delta (difference from best cumulative time)
+	// Calculate delta against the active comparison's cumulative time
 	var delta time.Duration
-	if ts.CurrentSplitIndex < len(ts.BestCumulativeTimes) && ts.BestCumulativeTimes[ts.CurrentSplitIndex] > 0 {
-		// Delta = current cumulative - best cumulative
+	comparisonTimes := ts.comparisonTimesFor(ts.Comparison)
+	if idx < len(comparisonTimes) && comparisonTimes[idx] > 0 {
 		// Negative = ahead (better), Positive = behind (worse)
-		delta = ts.CurrentTime - ts.BestCumulativeTimes[ts.CurrentSplitIndex]
+		delta = ts.CurrentTime - comparisonTimes[idx]
+	}
+	var previousCumulativeDelta time.Duration
+	if len(ts.Splits) > 0 {
+		previousCumulativeDelta = ts.Splits[len(ts.Splits)-1].Delta
 	}
 
 	// Update best times for this split
-	if ts.CurrentSplitIndex < len(ts.BestSplitTimes) {
-		// Update best segment time if this is better or first time
-		if ts.BestSplitTimes[ts.CurrentSplitIndex] == 0 || segmentTime < ts.BestSplitTimes[ts.CurrentSplitIndex] {
-			ts.BestSplitTimes[ts.CurrentSplitIndex] = segmentTime
-			ts.CalculateSumOfBest()
+	if idx < len(ts.BestSplitTimes) {
+		// Record this segment in the history and recompute the best segment/sum of
+		// best from it.
+		if idx < len(ts.SegmentHistory) {
+			ts.SegmentHistory[idx] = append(ts.SegmentHistory[idx], segmentTime)
 		}
+		ts.CalculateSumOfBest()
 
 		// Update best cumulative time if this is better or first time
-		if ts.BestCumulativeTimes[ts.CurrentSplitIndex] == 0 || ts.CurrentTime < ts.BestCumulativeTimes[ts.CurrentSplitIndex] {
-			ts.BestCumulativeTimes[ts.CurrentSplitIndex] = ts.CurrentTime
+		if ts.BestCumulativeTimes[idx] == 0 || ts.CurrentTime < ts.BestCumulativeTimes[idx] {
+			ts.BestCumulativeTimes[idx] = ts.CurrentTime
 		}
 	}
 
 	// Save the completed split
 	ts.Splits = append(ts.Splits, Split{
-		Name:           ts.PredefinedSplits[ts.CurrentSplitIndex].Name,
-		SegmentTime:    segmentTime,
-		CumulativeTime: ts.CurrentTime,
-		Delta:          delta,
+		Name:                       ts.PredefinedSplits[idx].Name,
+		SegmentTime:                segmentTime,
+		CumulativeTime:             ts.CurrentTime,
+		Delta:                      delta,
+		DeltaState:                 deltaState(segmentTime, previousBest, delta, previousCumulativeDelta),
+		TimeSaved:                  timeSaved(previousBest, segmentTime),
+		previousBestSplitTime:      previousBest,
+		previousBestCumulativeTime: previousBestCumulative,
+		previousPersonalBest:       previousPersonalBest,
+		previousPBSplitTimes:       previousPBSplitTimes,
 	})
 
 	// If this was the last split, stop the timer and check for new PB
@@ -166,9 +286,13 @@ func (ts *TimerState) NextSplit() {
 			for i, split := range ts.Splits {
 				ts.PBSplitTimes[i] = split.CumulativeTime
 			}
-			// Check if new PB beats world record
+			// Check if new PB beats world record, updating the per-split world
+			// record times in lockstep so the "worldRecord" comparison stays
+			// consistent with the advertised WorldRecord.
 			if ts.WorldRecord > 0 && ts.PersonalBest < ts.WorldRecord {
 				ts.WorldRecord = ts.PersonalBest
+				ts.WorldRecordSplits = make([]time.Duration, len(ts.PBSplitTimes))
+				copy(ts.WorldRecordSplits, ts.PBSplitTimes)
 			}
 		}
 		// Set index to -1 to indicate run is complete
@@ -179,25 +303,334 @@ func (ts *TimerState) NextSplit() {
 	}
 }
 
+// SkipSplit advances past the current split without recording a real time for it. It
+// appends a sentinel split (SegmentTime/CumulativeTime both SkippedSegmentTime) and
+// marks the segment history entry as skipped, so sum-of-best and statistics ignore
+// it, without touching BestSplitTimes or BestCumulativeTimes for this split.
+func (ts *TimerState) SkipSplit() {
+	if ts.Status != "running" || ts.CurrentSplitIndex < 0 || ts.CurrentSplitIndex >= len(ts.PredefinedSplits) {
+		return
+	}
+
+	idx := ts.CurrentSplitIndex
+
+	var previousBest, previousBestCumulative time.Duration
+	if idx < len(ts.BestSplitTimes) {
+		previousBest = ts.BestSplitTimes[idx]
+	}
+	if idx < len(ts.BestCumulativeTimes) {
+		previousBestCumulative = ts.BestCumulativeTimes[idx]
+	}
+
+	if idx < len(ts.SegmentHistory) {
+		ts.SegmentHistory[idx] = append(ts.SegmentHistory[idx], SkippedSegmentTime)
+		ts.CalculateSumOfBest()
+	}
+
+	ts.Splits = append(ts.Splits, Split{
+		Name:                       ts.PredefinedSplits[idx].Name,
+		SegmentTime:                SkippedSegmentTime,
+		CumulativeTime:             SkippedSegmentTime,
+		previousBestSplitTime:      previousBest,
+		previousBestCumulativeTime: previousBestCumulative,
+		previousPersonalBest:       ts.PersonalBest,
+		previousPBSplitTimes:       append([]time.Duration(nil), ts.PBSplitTimes...),
+	})
+
+	if idx == len(ts.PredefinedSplits)-1 {
+		ts.Status = "stopped"
+		ts.CurrentSplitIndex = -1
+	} else {
+		ts.CurrentSplitIndex++
+	}
+}
+
+// lastRealCumulativeTime returns the CumulativeTime of the most recently completed
+// split that wasn't skipped, walking backward past any SkippedSegmentTime entries. It
+// is 0 if there is no such split yet, which is what a segment timed from the very
+// start of the run needs.
+func (ts *TimerState) lastRealCumulativeTime() time.Duration {
+	for i := len(ts.Splits) - 1; i >= 0; i-- {
+		if ts.Splits[i].CumulativeTime != SkippedSegmentTime {
+			return ts.Splits[i].CumulativeTime
+		}
+	}
+	return 0
+}
+
+// UndoSplit reverts the most recently completed (or skipped) split: it pops the
+// split, restores CurrentSplitIndex, and rolls back the segment history entry and
+// any best times or personal best that split had just set, using the values
+// stashed on the split itself.
+func (ts *TimerState) UndoSplit() {
+	if len(ts.Splits) == 0 {
+		return
+	}
+
+	idx := len(ts.Splits) - 1
+	last := ts.Splits[idx]
+	ts.Splits = ts.Splits[:idx]
+
+	if idx < len(ts.SegmentHistory) && len(ts.SegmentHistory[idx]) > 0 {
+		ts.SegmentHistory[idx] = ts.SegmentHistory[idx][:len(ts.SegmentHistory[idx])-1]
+	}
+	if idx < len(ts.BestSplitTimes) {
+		ts.BestSplitTimes[idx] = last.previousBestSplitTime
+	}
+	if idx < len(ts.BestCumulativeTimes) {
+		ts.BestCumulativeTimes[idx] = last.previousBestCumulativeTime
+	}
+	ts.CalculateSumOfBest()
+
+	if ts.Status == "stopped" {
+		// The run had finished on this split; undoing it resumes the run and
+		// reverts the personal best it may have just set.
+		ts.PersonalBest = last.previousPersonalBest
+		ts.PBSplitTimes = last.previousPBSplitTimes
+		ts.Status = "running"
+	}
+	ts.CurrentSplitIndex = idx
+}
+
 // Update updates the current time if running
 func (ts *TimerState) Update() {
-	if ts.Status == "running" {
-		ts.CurrentTime = time.Since(ts.StartTime)
+	if ts.Status != "running" {
+		return
+	}
+	ts.CurrentTime = time.Since(ts.StartTime)
+
+	if ts.GameTimePaused {
+		// Keep GameTime frozen by letting the offset absorb the elapsed real time.
+		ts.GameTimeOffset = ts.CurrentTime - ts.GameTime
+	} else {
+		ts.GameTime = ts.CurrentTime - ts.GameTimeOffset
 	}
 }
 
+// PauseGameTime freezes the independent game-time clock (e.g. during a loading
+// screen) without affecting the real-time clock.
+func (ts *TimerState) PauseGameTime() {
+	ts.GameTimePaused = true
+}
+
+// ResumeGameTime unfreezes the game-time clock so it resumes ticking alongside the
+// real-time clock.
+func (ts *TimerState) ResumeGameTime() {
+	ts.GameTimePaused = false
+}
+
 // ToJSON serializes the timer state to JSON
 func (ts *TimerState) ToJSON() []byte {
+	ts.ComparisonTimes = ts.comparisonTimesFor(ts.Comparison)
 	data, _ := json.Marshal(ts)
 	return data
 }
 
-// CalculateSumOfBest computes the sum of all best segment times
+// comparisonTimesFor computes the cumulative time for each split under the given
+// comparison mode. Best Segments and Sum of Best are both the cumulative sum of
+// BestSplitTimes, shown under different labels to the frontend (the latter as the
+// goal pace); Average sums the mean segment from SegmentStats; World Record and PB
+// are simply the stored split times for their respective runs.
+func (ts *TimerState) comparisonTimesFor(comparison string) []time.Duration {
+	switch comparison {
+	case ComparisonBestSegments, ComparisonSumOfBest:
+		times := make([]time.Duration, len(ts.BestSplitTimes))
+		var cumulative time.Duration
+		for i, best := range ts.BestSplitTimes {
+			cumulative += best
+			times[i] = cumulative
+		}
+		return times
+	case ComparisonAverage:
+		times := make([]time.Duration, len(ts.SegmentStats))
+		var cumulative time.Duration
+		for i, stat := range ts.SegmentStats {
+			cumulative += stat.Average
+			times[i] = cumulative
+		}
+		return times
+	case ComparisonWorldRecord:
+		return ts.WorldRecordSplits
+	default:
+		return ts.PBSplitTimes
+	}
+}
+
+// deltaState derives the LiveSplit-style color state for a completed split: "gold" if
+// it beat the all-time best segment, otherwise one of the ahead/behind ×
+// gaining/losing combinations. Gaining/losing compares this split's cumulative delta
+// against the previous split's cumulative delta (did this segment close the gap with
+// the comparison or widen it), not against the all-time best segment — a segment can
+// be slower than the best ever run and still be gaining ground on the comparison.
+func deltaState(segmentTime, previousBest, cumulativeDelta, previousCumulativeDelta time.Duration) string {
+	if previousBest > 0 && segmentTime > 0 && segmentTime < previousBest {
+		return DeltaGold
+	}
+
+	ahead := cumulativeDelta <= 0
+	gaining := cumulativeDelta <= previousCumulativeDelta
+
+	switch {
+	case ahead && gaining:
+		return DeltaAheadGaining
+	case ahead && !gaining:
+		return DeltaAheadLosing
+	case !ahead && gaining:
+		return DeltaBehindGaining
+	default:
+		return DeltaBehindLosing
+	}
+}
+
+// timeSaved returns how much faster (positive) or slower (negative) a split's
+// segment was compared to the best segment time recorded prior to this attempt.
+func timeSaved(previousBest, segmentTime time.Duration) time.Duration {
+	if previousBest <= 0 {
+		return 0
+	}
+	return previousBest - segmentTime
+}
+
+// CalculateSumOfBest recomputes BestSplitTimes from the full segment history (taking
+// the minimum non-skipped time recorded for each split) and sums the result into
+// SumOfBest. Recomputing from history, rather than only tracking the running best,
+// is what lets CleanSumOfBest retroactively discard bogus segments.
 func (ts *TimerState) CalculateSumOfBest() {
+	for i := range ts.BestSplitTimes {
+		if i >= len(ts.SegmentHistory) {
+			continue
+		}
+		var best time.Duration
+		for _, t := range ts.SegmentHistory[i] {
+			if t <= 0 {
+				continue // skipped, or never recorded
+			}
+			if best == 0 || t < best {
+				best = t
+			}
+		}
+		if best > 0 {
+			ts.BestSplitTimes[i] = best
+		}
+	}
+
 	ts.SumOfBest = 0
 	for _, bestTime := range ts.BestSplitTimes {
 		ts.SumOfBest += bestTime
 	}
+
+	ts.updateSegmentStats()
+}
+
+// validSegmentTimes returns the non-skipped segment times recorded for split i.
+func (ts *TimerState) validSegmentTimes(i int) []time.Duration {
+	if i >= len(ts.SegmentHistory) {
+		return nil
+	}
+	var valid []time.Duration
+	for _, t := range ts.SegmentHistory[i] {
+		if t > 0 {
+			valid = append(valid, t)
+		}
+	}
+	return valid
+}
+
+// AverageSegment returns the mean recorded segment time for split i, ignoring
+// skipped segments. ok is false if there is no data yet.
+func (ts *TimerState) AverageSegment(i int) (avg time.Duration, ok bool) {
+	times := ts.validSegmentTimes(i)
+	if len(times) == 0 {
+		return 0, false
+	}
+	var sum time.Duration
+	for _, t := range times {
+		sum += t
+	}
+	return sum / time.Duration(len(times)), true
+}
+
+// MedianSegment returns the median recorded segment time for split i, ignoring
+// skipped segments. ok is false if there is no data yet.
+func (ts *TimerState) MedianSegment(i int) (median time.Duration, ok bool) {
+	times := ts.validSegmentTimes(i)
+	if len(times) == 0 {
+		return 0, false
+	}
+	sorted := append([]time.Duration(nil), times...)
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a] < sorted[b] })
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2, true
+	}
+	return sorted[mid], true
+}
+
+// StdDevSegment returns the standard deviation of the recorded segment times for
+// split i, ignoring skipped segments. ok is false if there are fewer than two
+// samples to compute a deviation from.
+func (ts *TimerState) StdDevSegment(i int) (stdDev time.Duration, ok bool) {
+	times := ts.validSegmentTimes(i)
+	if len(times) < 2 {
+		return 0, false
+	}
+	avg, _ := ts.AverageSegment(i)
+
+	var variance float64
+	for _, t := range times {
+		diff := float64(t - avg)
+		variance += diff * diff
+	}
+	variance /= float64(len(times))
+
+	return time.Duration(math.Sqrt(variance)), true
+}
+
+// updateSegmentStats recomputes SegmentStats from the current segment history.
+func (ts *TimerState) updateSegmentStats() {
+	ts.SegmentStats = make([]SegmentStat, len(ts.PredefinedSplits))
+	for i := range ts.SegmentStats {
+		avg, _ := ts.AverageSegment(i)
+		median, _ := ts.MedianSegment(i)
+		stdDev, _ := ts.StdDevSegment(i)
+
+		var possibleTimeSave time.Duration
+		if best := ts.BestSplitTimes; i < len(best) && best[i] > 0 && avg > best[i] {
+			possibleTimeSave = avg - best[i]
+		}
+
+		ts.SegmentStats[i] = SegmentStat{
+			Average:          avg,
+			Median:           median,
+			StdDev:           stdDev,
+			PossibleTimeSave: possibleTimeSave,
+		}
+	}
+}
+
+// CleanSumOfBest discards segment history entries that look like accidental
+// double-taps: any recorded time shorter than threshold×median for its split is
+// dropped, then BestSplitTimes, SumOfBest and SegmentStats are recomputed.
+func (ts *TimerState) CleanSumOfBest(threshold float64) {
+	for i, times := range ts.SegmentHistory {
+		median, ok := ts.MedianSegment(i)
+		if !ok {
+			continue
+		}
+		cutoff := time.Duration(float64(median) * threshold)
+
+		cleaned := times[:0:0]
+		for _, t := range times {
+			if t > 0 && t < cutoff {
+				continue // outlier, likely a double-tap
+			}
+			cleaned = append(cleaned, t)
+		}
+		ts.SegmentHistory[i] = cleaned
+	}
+
+	ts.CalculateSumOfBest()
 }
 
 // GetCurrentDelta returns the delta for the current running split
@@ -209,17 +642,18 @@ func (ts *TimerState) GetCurrentDelta() time.Duration {
 
 	// Calculate what the next split index would be (the one we're currently working on)
 	nextSplitIndex := ts.CurrentSplitIndex + 1
-	if nextSplitIndex >= len(ts.BestCumulativeTimes) {
+	comparisonTimes := ts.comparisonTimesFor(ts.Comparison)
+	if nextSplitIndex >= len(comparisonTimes) {
 		return 0
 	}
 
-	// If we don't have a best time for this split yet, no delta to show
-	if ts.BestCumulativeTimes[nextSplitIndex] == 0 {
+	// If we don't have a comparison time for this split yet, no delta to show
+	if comparisonTimes[nextSplitIndex] == 0 {
 		return 0
 	}
 
-	// Delta = current time - best cumulative time for next split
-	return ts.CurrentTime - ts.BestCumulativeTimes[nextSplitIndex]
+	// Delta = current time - comparison's cumulative time for next split
+	return ts.CurrentTime - comparisonTimes[nextSplitIndex]
 }
 
 // RestorePBData restores personal best data from imported file
@@ -269,3 +703,27 @@ func (ts *TimerState) RestorePBData(cmd map[string]interface{}) {
 		ts.WorldRecord = time.Duration(worldRecord)
 	}
 }
+
+// ApplyImportedSplits replaces the predefined splits and best-time data with those
+// recovered from an imported run (e.g. a LiveSplit .lss file). It is the .lss
+// counterpart to RestorePBData, which restores from the app's own JSON blob. The
+// segment history recorded for the previous splits is cleared.
+func (ts *TimerState) ApplyImportedSplits(imported *ImportedSplits) {
+	ts.SetPredefinedSplits(imported.PredefinedSplits, imported.Title)
+	ts.SegmentHistory = make([][]time.Duration, len(imported.PredefinedSplits))
+
+	ts.BestSplitTimes = make([]time.Duration, len(imported.BestSplitTimes))
+	copy(ts.BestSplitTimes, imported.BestSplitTimes)
+
+	ts.PBSplitTimes = make([]time.Duration, len(imported.PBSplitTimes))
+	copy(ts.PBSplitTimes, imported.PBSplitTimes)
+
+	ts.BestCumulativeTimes = make([]time.Duration, len(imported.PBSplitTimes))
+	copy(ts.BestCumulativeTimes, imported.PBSplitTimes)
+
+	if n := len(ts.PBSplitTimes); n > 0 {
+		ts.PersonalBest = ts.PBSplitTimes[n-1]
+	}
+
+	ts.CalculateSumOfBest()
+}
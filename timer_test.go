@@ -0,0 +1,320 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func newStateWithSplits(n int) *TimerState {
+	ts := NewTimerState()
+	defs := make([]SplitDefinition, n)
+	for i := range defs {
+		defs[i] = SplitDefinition{Name: "Split"}
+	}
+	ts.SetPredefinedSplits(defs, "Test")
+	return ts
+}
+
+func TestCalculateSumOfBest_EmptyHistory(t *testing.T) {
+	ts := newStateWithSplits(3)
+
+	ts.CalculateSumOfBest()
+
+	if ts.SumOfBest != 0 {
+		t.Errorf("SumOfBest = %v, want 0", ts.SumOfBest)
+	}
+	for i, best := range ts.BestSplitTimes {
+		if best != 0 {
+			t.Errorf("BestSplitTimes[%d] = %v, want 0", i, best)
+		}
+	}
+}
+
+func TestCalculateSumOfBest_SingleAttempt(t *testing.T) {
+	ts := newStateWithSplits(2)
+	ts.SegmentHistory[0] = []time.Duration{10 * time.Second}
+	ts.SegmentHistory[1] = []time.Duration{20 * time.Second}
+
+	ts.CalculateSumOfBest()
+
+	want := 30 * time.Second
+	if ts.SumOfBest != want {
+		t.Errorf("SumOfBest = %v, want %v", ts.SumOfBest, want)
+	}
+}
+
+func TestCalculateSumOfBest_IgnoresSkippedSegments(t *testing.T) {
+	ts := newStateWithSplits(1)
+	ts.SegmentHistory[0] = []time.Duration{15 * time.Second, SkippedSegmentTime, 10 * time.Second}
+
+	ts.CalculateSumOfBest()
+
+	want := 10 * time.Second
+	if ts.BestSplitTimes[0] != want {
+		t.Errorf("BestSplitTimes[0] = %v, want %v", ts.BestSplitTimes[0], want)
+	}
+	if ts.SumOfBest != want {
+		t.Errorf("SumOfBest = %v, want %v", ts.SumOfBest, want)
+	}
+}
+
+func TestCalculateSumOfBest_AllSkippedLeavesBestUnset(t *testing.T) {
+	ts := newStateWithSplits(1)
+	ts.SegmentHistory[0] = []time.Duration{SkippedSegmentTime, SkippedSegmentTime}
+
+	ts.CalculateSumOfBest()
+
+	if ts.BestSplitTimes[0] != 0 {
+		t.Errorf("BestSplitTimes[0] = %v, want 0", ts.BestSplitTimes[0])
+	}
+}
+
+func TestMedianAndAverageSegment(t *testing.T) {
+	ts := newStateWithSplits(1)
+	ts.SegmentHistory[0] = []time.Duration{
+		10 * time.Second, 20 * time.Second, 30 * time.Second, SkippedSegmentTime,
+	}
+
+	avg, ok := ts.AverageSegment(0)
+	if !ok || avg != 20*time.Second {
+		t.Errorf("AverageSegment = %v, %v; want 20s, true", avg, ok)
+	}
+
+	median, ok := ts.MedianSegment(0)
+	if !ok || median != 20*time.Second {
+		t.Errorf("MedianSegment = %v, %v; want 20s, true", median, ok)
+	}
+}
+
+func TestMedianSegment_NoData(t *testing.T) {
+	ts := newStateWithSplits(1)
+
+	if _, ok := ts.MedianSegment(0); ok {
+		t.Error("MedianSegment should report no data for an empty history")
+	}
+}
+
+func TestCleanSumOfBest_DropsOutliers(t *testing.T) {
+	ts := newStateWithSplits(1)
+	// Median of these four is 20s; a 2s segment is a clear accidental double-tap.
+	ts.SegmentHistory[0] = []time.Duration{
+		20 * time.Second, 20 * time.Second, 22 * time.Second, 2 * time.Second,
+	}
+
+	ts.CleanSumOfBest(DefaultCleanSumOfBestThreshold)
+
+	if len(ts.SegmentHistory[0]) != 3 {
+		t.Fatalf("len(SegmentHistory[0]) = %d, want 3 after dropping the outlier", len(ts.SegmentHistory[0]))
+	}
+	if ts.BestSplitTimes[0] != 20*time.Second {
+		t.Errorf("BestSplitTimes[0] = %v, want 20s", ts.BestSplitTimes[0])
+	}
+}
+
+func TestComparisonTimesFor_BestSegmentsAndSumOfBest(t *testing.T) {
+	ts := newStateWithSplits(2)
+	ts.BestSplitTimes = []time.Duration{10 * time.Second, 15 * time.Second}
+
+	want := []time.Duration{10 * time.Second, 25 * time.Second}
+	for _, mode := range []string{ComparisonBestSegments, ComparisonSumOfBest} {
+		got := ts.comparisonTimesFor(mode)
+		if got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("comparisonTimesFor(%q) = %v, want %v", mode, got, want)
+		}
+	}
+}
+
+func TestComparisonTimesFor_WorldRecord(t *testing.T) {
+	ts := newStateWithSplits(2)
+	ts.WorldRecordSplits = []time.Duration{5 * time.Second, 12 * time.Second}
+
+	got := ts.comparisonTimesFor(ComparisonWorldRecord)
+	if got[0] != 5*time.Second || got[1] != 12*time.Second {
+		t.Errorf("comparisonTimesFor(worldRecord) = %v, want [5s 12s]", got)
+	}
+}
+
+func TestDeltaState(t *testing.T) {
+	cases := []struct {
+		name                    string
+		segmentTime             time.Duration
+		previousBest            time.Duration
+		cumulativeDelta         time.Duration
+		previousCumulativeDelta time.Duration
+		want                    string
+	}{
+		{"new best segment is gold", 9 * time.Second, 10 * time.Second, -time.Second, -time.Second, DeltaGold},
+		{"ahead and gaining ground", 10 * time.Second, 10 * time.Second, -2 * time.Second, -time.Second, DeltaAheadGaining},
+		{"ahead but losing ground", 10 * time.Second, 10 * time.Second, -time.Second, -2 * time.Second, DeltaAheadLosing},
+		{"behind but gaining ground", 10 * time.Second, 10 * time.Second, time.Second, 2 * time.Second, DeltaBehindGaining},
+		{"behind and losing further ground", 10 * time.Second, 10 * time.Second, 2 * time.Second, time.Second, DeltaBehindLosing},
+		{"behind but catching up still beats best segment -> gold", 9 * time.Second, 10 * time.Second, time.Second, 2 * time.Second, DeltaGold},
+	}
+
+	for _, c := range cases {
+		got := deltaState(c.segmentTime, c.previousBest, c.cumulativeDelta, c.previousCumulativeDelta)
+		if got != c.want {
+			t.Errorf("%s: deltaState() = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestTimeSaved(t *testing.T) {
+	if got := timeSaved(10*time.Second, 8*time.Second); got != 2*time.Second {
+		t.Errorf("timeSaved() = %v, want 2s", got)
+	}
+	if got := timeSaved(0, 8*time.Second); got != 0 {
+		t.Errorf("timeSaved() with no previous best = %v, want 0", got)
+	}
+}
+
+func TestNextSplit_NewPBBeatingWorldRecordUpdatesWorldRecordSplits(t *testing.T) {
+	ts := newStateWithSplits(1)
+	ts.WorldRecord = 100 * time.Second
+	ts.WorldRecordSplits = []time.Duration{100 * time.Second}
+	ts.Start()
+	ts.StartTime = time.Now().Add(-5 * time.Second)
+
+	ts.NextSplit() // the only split, finished in ~5s -> new PB beats the stored WR
+
+	if ts.WorldRecord != ts.PersonalBest {
+		t.Fatalf("WorldRecord = %v, want it to match the new PersonalBest %v", ts.WorldRecord, ts.PersonalBest)
+	}
+	if len(ts.WorldRecordSplits) != 1 || ts.WorldRecordSplits[0] != ts.PBSplitTimes[0] {
+		t.Errorf("WorldRecordSplits = %v, want %v (the new PB run)", ts.WorldRecordSplits, ts.PBSplitTimes)
+	}
+	if ts.WorldRecordSplits[len(ts.WorldRecordSplits)-1] > ts.WorldRecord {
+		t.Errorf("WorldRecordSplits final cumulative %v exceeds the advertised WorldRecord %v", ts.WorldRecordSplits[len(ts.WorldRecordSplits)-1], ts.WorldRecord)
+	}
+}
+
+func TestSkipSplit_AppendsSentinelAndAdvancesWithoutTouchingBestTimes(t *testing.T) {
+	ts := newStateWithSplits(2)
+	ts.BestSplitTimes[0] = 5 * time.Second
+	ts.Start()
+
+	ts.SkipSplit()
+
+	if len(ts.Splits) != 1 {
+		t.Fatalf("len(Splits) = %d, want 1", len(ts.Splits))
+	}
+	if ts.Splits[0].SegmentTime != SkippedSegmentTime || ts.Splits[0].CumulativeTime != SkippedSegmentTime {
+		t.Errorf("skipped split = %+v, want sentinel times", ts.Splits[0])
+	}
+	if ts.BestSplitTimes[0] != 5*time.Second {
+		t.Errorf("BestSplitTimes[0] = %v, want unchanged 5s", ts.BestSplitTimes[0])
+	}
+	if ts.CurrentSplitIndex != 1 {
+		t.Errorf("CurrentSplitIndex = %d, want 1", ts.CurrentSplitIndex)
+	}
+}
+
+func TestSkipSplit_NextSegmentTimeIsMeasuredFromLastRealCumulative(t *testing.T) {
+	ts := newStateWithSplits(3)
+	ts.Start()
+
+	ts.StartTime = time.Now().Add(-10 * time.Second)
+	ts.NextSplit() // split 0 completes at ~10s
+
+	ts.SkipSplit() // split 1 skipped; its CumulativeTime is the sentinel
+
+	ts.StartTime = time.Now().Add(-30 * time.Second)
+	ts.NextSplit() // split 2 completes at ~30s
+
+	got := ts.Splits[2].SegmentTime
+	want := 20 * time.Second
+	tolerance := 200 * time.Millisecond
+	if got < want-tolerance || got > want+tolerance {
+		t.Errorf("split 2 SegmentTime = %v, want ~%v (measured from split 0's cumulative, skipping over the sentinel)", got, want)
+	}
+}
+
+func TestUndoSplit_RestoresIndexAndBestSplitTime(t *testing.T) {
+	ts := newStateWithSplits(2)
+	ts.Start()
+	time.Sleep(2 * time.Millisecond)
+	ts.NextSplit() // completes split 0, likely sets a new best segment time
+
+	newBest := ts.BestSplitTimes[0]
+	if newBest <= 0 {
+		t.Fatalf("expected split 0 to set a best segment time, got %v", newBest)
+	}
+
+	ts.UndoSplit()
+
+	if ts.CurrentSplitIndex != 0 {
+		t.Errorf("CurrentSplitIndex = %d, want 0", ts.CurrentSplitIndex)
+	}
+	if len(ts.Splits) != 0 {
+		t.Errorf("len(Splits) = %d, want 0", len(ts.Splits))
+	}
+	if ts.BestSplitTimes[0] != 0 {
+		t.Errorf("BestSplitTimes[0] = %v, want 0 (no best before this attempt)", ts.BestSplitTimes[0])
+	}
+	if len(ts.SegmentHistory[0]) != 0 {
+		t.Errorf("len(SegmentHistory[0]) = %d, want 0", len(ts.SegmentHistory[0]))
+	}
+}
+
+func TestUndoSplit_OnFinishedRunResumesAndRevertsPersonalBest(t *testing.T) {
+	ts := newStateWithSplits(1)
+	ts.Start()
+	time.Sleep(2 * time.Millisecond)
+	ts.NextSplit() // the only split -> finishes the run and sets a PB
+
+	if ts.Status != "stopped" || ts.PersonalBest == 0 {
+		t.Fatalf("expected a finished run with a PB, got status=%q pb=%v", ts.Status, ts.PersonalBest)
+	}
+
+	ts.UndoSplit()
+
+	if ts.Status != "running" {
+		t.Errorf("Status = %q, want running", ts.Status)
+	}
+	if ts.PersonalBest != 0 {
+		t.Errorf("PersonalBest = %v, want reverted to 0", ts.PersonalBest)
+	}
+	if ts.CurrentSplitIndex != 0 {
+		t.Errorf("CurrentSplitIndex = %d, want 0", ts.CurrentSplitIndex)
+	}
+}
+
+func TestUndoSplit_Empty(t *testing.T) {
+	ts := newStateWithSplits(2)
+	ts.UndoSplit() // should not panic on an empty split list
+	if len(ts.Splits) != 0 {
+		t.Errorf("len(Splits) = %d, want 0", len(ts.Splits))
+	}
+}
+
+func TestGameTime_PausesIndependentlyOfRealTime(t *testing.T) {
+	ts := newStateWithSplits(1)
+	ts.Start()
+	time.Sleep(3 * time.Millisecond)
+	ts.Update()
+
+	gameTimeAtPause := ts.GameTime
+	if gameTimeAtPause <= 0 {
+		t.Fatalf("expected GameTime to have advanced, got %v", gameTimeAtPause)
+	}
+
+	ts.PauseGameTime()
+	time.Sleep(3 * time.Millisecond)
+	ts.Update()
+	ts.Update()
+
+	if ts.GameTime != gameTimeAtPause {
+		t.Errorf("GameTime = %v while paused, want unchanged %v", ts.GameTime, gameTimeAtPause)
+	}
+
+	ts.ResumeGameTime()
+	time.Sleep(3 * time.Millisecond)
+	ts.Update()
+
+	if ts.GameTime <= gameTimeAtPause {
+		t.Errorf("GameTime = %v after resuming, want greater than %v", ts.GameTime, gameTimeAtPause)
+	}
+	if ts.CurrentTime <= ts.GameTime {
+		t.Errorf("CurrentTime = %v should have outpaced GameTime = %v due to the pause", ts.CurrentTime, ts.GameTime)
+	}
+}